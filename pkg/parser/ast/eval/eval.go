@@ -0,0 +1,171 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval compiles an ast.Expr into a tree of typed evaluator
+// closures, so that WHERE/SELECT fragments can be run directly in Go
+// instead of always being pushed down to the SQL engine.  The design
+// mirrors the closure-per-node expr struct in Go's exp/eval: Compile
+// walks the Expr once and returns an Expr holding an Eval(t *Thread)
+// closure; there is no further tree-walking at evaluation time.
+package eval
+
+import (
+	"fmt"
+
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+// Type identifies the runtime type a compiled Expr evaluates to.
+type Type int
+
+// The result types a compiled Expr can hold.
+const (
+	Invalid Type = iota
+	Int64
+	Float64
+	String
+	Bool
+	Timestamp
+)
+
+// Thread carries the state a compiled Expr needs while it runs: the
+// current row, addressed by column/variable name, and a scratch stack
+// compiled closures may use to pass intermediate results around without
+// allocating.
+type Thread struct {
+	Row   map[string]interface{}
+	stack []interface{}
+}
+
+// NewThread returns a Thread that evaluates expressions against row.
+func NewThread(row map[string]interface{}) *Thread {
+	return &Thread{Row: row}
+}
+
+func (t *Thread) push(v interface{}) {
+	t.stack = append(t.stack, v)
+}
+
+func (t *Thread) pop() interface{} {
+	v := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	return v
+}
+
+// Addr lets a compiled Expr be used as an assignable reference, e.g. a
+// column or variable slot on the left-hand side of an assignment.
+type Addr struct {
+	Get func(t *Thread) interface{}
+	Set func(t *Thread, v interface{})
+}
+
+// Expr is a compiled ast.Expr: Type is its resolved result type, Eval
+// evaluates it against a Thread, and EvalAddr, if non-nil, lets callers
+// treat it as an assignable reference.
+type Expr struct {
+	Type     Type
+	Eval     func(t *Thread) interface{}
+	EvalAddr func(t *Thread) *Addr
+}
+
+// Compiler compiles ast.Expr trees into Expr closures, resolving
+// funcalls against Funcs.
+type Compiler struct {
+	Funcs FuncRegistry
+}
+
+// NewCompiler returns a Compiler that resolves funcalls against
+// DefaultFuncs.
+func NewCompiler() *Compiler {
+	return &Compiler{Funcs: DefaultFuncs}
+}
+
+// Compile compiles e into an Expr.
+func (c *Compiler) Compile(e *ast.Expr) (*Expr, error) {
+	switch {
+	case e.IsLiteral():
+		return c.compileLiteral(e)
+	case e.IsFuncall() && isFlattenedLogical(e):
+		// ast.Fold flattens a chain of AND/OR into an n-ary node that
+		// reuses the funcall representation (see ast.flattenAssoc); it
+		// is not a call to a registered function, so compile it as a
+		// logical chain instead of going through compileFuncall.
+		return c.compileLogicalChain(e)
+	case e.IsFuncall():
+		return c.compileFuncall(e)
+	case e.IsVariadic():
+		return c.compileVariadic(e)
+	case e.IsUnary():
+		return c.compileUnary(e)
+	case e.IsBinary():
+		return c.compileBinary(e)
+	}
+	return nil, fmt.Errorf("eval: cannot compile expression %q", e)
+}
+
+// isFlattenedLogical reports whether e is a flattened AND/OR chain
+// produced by ast.Fold, as opposed to an actual funcall to a builtin
+// named "AND" or "OR" (which cannot exist: AND/OR are reserved
+// operators, not identifiers the parser accepts as a function name).
+func isFlattenedLogical(e *ast.Expr) bool {
+	return e.Operator() == "AND" || e.Operator() == "OR"
+}
+
+func (c *Compiler) compileLiteral(e *ast.Expr) (*Expr, error) {
+	switch e.Typ() {
+	case ast.NUMBER:
+		return compileNumber(e.Val())
+	case ast.STRING:
+		return compileString(e.Val())
+	case ast.IDENT:
+		return compileIdent(e.Val()), nil
+	default:
+		return nil, fmt.Errorf("eval: unsupported literal type %d in %q", e.Typ(), e)
+	}
+}
+
+func compileIdent(name string) *Expr {
+	return &Expr{
+		Type: Invalid, // resolved against a schema by ast/types, not here
+		Eval: func(t *Thread) interface{} {
+			return t.Row[name]
+		},
+		EvalAddr: func(t *Thread) *Addr {
+			return &Addr{
+				Get: func(t *Thread) interface{} { return t.Row[name] },
+				Set: func(t *Thread, v interface{}) { t.Row[name] = v },
+			}
+		},
+	}
+}
+
+func (c *Compiler) compileVariadic(e *ast.Expr) (*Expr, error) {
+	elems := make([]*Expr, len(e.Operands()))
+	for i, od := range e.Operands() {
+		ce, err := c.Compile(od)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = ce
+	}
+	return &Expr{
+		Type: Invalid, // a slice has no scalar Type
+		Eval: func(t *Thread) interface{} {
+			v := make([]interface{}, len(elems))
+			for i, ce := range elems {
+				v[i] = ce.Eval(t)
+			}
+			return v
+		},
+	}, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"testing"
+
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+func num(v string) *ast.Expr {
+	e, err := ast.NewLiteral(ast.NUMBER, v)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func ident(name string) *ast.Expr {
+	e, err := ast.NewLiteral(ast.IDENT, name)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// binary builds a binary Expr for op; the operator literal's typ is
+// otherwise only meaningful to the goyacc-generated parser, so any
+// non-zero placeholder works here.
+func binary(op string, l, r *ast.Expr) *ast.Expr {
+	e, err := ast.NewBinary(int(op[0]), op, l, r)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func compileAndEval(t *testing.T, e *ast.Expr, row map[string]interface{}) (*Expr, interface{}) {
+	ce, err := NewCompiler().Compile(e)
+	if err != nil {
+		t.Fatalf("Compile(%v) returned error: %v", e, err)
+	}
+	return ce, ce.Eval(NewThread(row))
+}
+
+func TestCompileArithIntegrality(t *testing.T) {
+	cases := []struct {
+		expr     *ast.Expr
+		wantType Type
+		want     interface{}
+	}{
+		{binary("+", num("1"), num("2")), Int64, int64(3)},
+		{binary("+", num("1"), num("2.5")), Float64, 3.5},
+		{binary("*", num("3"), num("4")), Int64, int64(12)},
+		{binary("/", num("7"), num("2")), Int64, int64(3)},
+	}
+	for _, c := range cases {
+		ce, got := compileAndEval(t, c.expr, nil)
+		if ce.Type != c.wantType {
+			t.Errorf("%v: Type = %v, want %v", c.expr, ce.Type, c.wantType)
+		}
+		if got != c.want {
+			t.Errorf("%v: Eval = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestCompileIdentReadsRow(t *testing.T) {
+	_, got := compileAndEval(t, ident("age"), map[string]interface{}{"age": int64(30)})
+	if got != int64(30) {
+		t.Errorf("Eval(age) = %v, want 30", got)
+	}
+}
+
+func TestCompileCompareString(t *testing.T) {
+	e := binary("=", ident("name"), func() *ast.Expr {
+		e, _ := ast.NewLiteral(ast.STRING, "'bob'")
+		return e
+	}())
+	_, got := compileAndEval(t, e, map[string]interface{}{"name": "bob"})
+	if got != true {
+		t.Errorf("Eval(name = 'bob') = %v, want true", got)
+	}
+}
+
+func TestCompileLogical(t *testing.T) {
+	e := binary("AND", ident("a"), ident("b"))
+	_, got := compileAndEval(t, e, map[string]interface{}{"a": true, "b": false})
+	if got != false {
+		t.Errorf("Eval(a AND b) = %v, want false", got)
+	}
+}
+
+func TestCompileFuncallUnknown(t *testing.T) {
+	fn, err := ast.NewFuncall(ast.IDENT, "NOPE", ast.ExprList{num("1")})
+	if err != nil {
+		t.Fatalf("NewFuncall returned error: %v", err)
+	}
+	if _, err := NewCompiler().Compile(fn); err == nil {
+		t.Fatal("Compile(NOPE(1)) should have returned an error")
+	}
+}
+
+func TestCompileFlattenedLogicalChain(t *testing.T) {
+	// Regression test: ast.Fold flattens chained AND/OR into an n-ary
+	// node that reuses the funcall representation (Operator() "AND"),
+	// which used to make Compile treat it as a call to an unregistered
+	// function named "AND" and fail.
+	a, b, c := ident("a"), ident("b"), ident("c")
+	chain, err := ast.NewFuncall(int('A'), "AND", ast.ExprList{a, b, c})
+	if err != nil {
+		t.Fatalf("NewFuncall returned error: %v", err)
+	}
+	_, got := compileAndEval(t, chain, map[string]interface{}{"a": true, "b": true, "c": false})
+	if got != false {
+		t.Errorf("Eval(a AND b AND c) = %v, want false", got)
+	}
+}
+
+func TestCompileVariadic(t *testing.T) {
+	v, err := ast.NewVariadic('[', "[", ast.ExprList{num("1"), num("2"), num("3")})
+	if err != nil {
+		t.Fatalf("NewVariadic returned error: %v", err)
+	}
+	_, got := compileAndEval(t, v, nil)
+	slice, ok := got.([]interface{})
+	if !ok || len(slice) != 3 {
+		t.Fatalf("Eval([1,2,3]) = %v, want a 3-element slice", got)
+	}
+}
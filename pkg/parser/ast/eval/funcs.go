@@ -0,0 +1,115 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+// Builtin compiles a funcall given its already-compiled arguments.
+type Builtin func(args []*Expr) (*Expr, error)
+
+// FuncRegistry maps a builtin function name to its Builtin compiler.
+type FuncRegistry map[string]Builtin
+
+// DefaultFuncs is the FuncRegistry that NewCompiler installs.  A Compiler
+// whose Funcs is nil resolves no funcalls at all.
+var DefaultFuncs = FuncRegistry{
+	"ABS":    builtinAbs,
+	"LENGTH": builtinLength,
+	"UPPER":  builtinUpper,
+	"LOWER":  builtinLower,
+}
+
+func (c *Compiler) compileFuncall(e *ast.Expr) (*Expr, error) {
+	fn, ok := c.Funcs[e.Operator()]
+	if !ok {
+		return nil, fmt.Errorf("eval: unknown function %q", e.Operator())
+	}
+	args := make([]*Expr, len(e.Operands()))
+	for i, od := range e.Operands() {
+		ce, err := c.Compile(od)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = ce
+	}
+	return fn(args)
+}
+
+func builtinAbs(args []*Expr) (*Expr, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("eval: ABS takes exactly 1 argument, got %d", len(args))
+	}
+	a := args[0]
+	return &Expr{
+		Type: a.Type,
+		Eval: func(t *Thread) interface{} {
+			if a.Type == Int64 {
+				v := a.Eval(t).(int64)
+				if v < 0 {
+					return -v
+				}
+				return v
+			}
+			v := toFloat64(a.Eval(t))
+			if v < 0 {
+				return -v
+			}
+			return v
+		},
+	}, nil
+}
+
+func builtinLength(args []*Expr) (*Expr, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("eval: LENGTH takes exactly 1 argument, got %d", len(args))
+	}
+	a := args[0]
+	return &Expr{
+		Type: Int64,
+		Eval: func(t *Thread) interface{} {
+			return int64(len(a.Eval(t).(string)))
+		},
+	}, nil
+}
+
+func builtinUpper(args []*Expr) (*Expr, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("eval: UPPER takes exactly 1 argument, got %d", len(args))
+	}
+	a := args[0]
+	return &Expr{
+		Type: String,
+		Eval: func(t *Thread) interface{} {
+			return strings.ToUpper(a.Eval(t).(string))
+		},
+	}, nil
+}
+
+func builtinLower(args []*Expr) (*Expr, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("eval: LOWER takes exactly 1 argument, got %d", len(args))
+	}
+	a := args[0]
+	return &Expr{
+		Type: String,
+		Eval: func(t *Thread) interface{} {
+			return strings.ToLower(a.Eval(t).(string))
+		},
+	}, nil
+}
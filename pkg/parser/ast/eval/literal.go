@@ -0,0 +1,57 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func compileNumber(val string) (*Expr, error) {
+	if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return &Expr{Type: Int64, Eval: func(*Thread) interface{} { return i }}, nil
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, fmt.Errorf("eval: %q is not a valid number literal: %v", val, err)
+	}
+	return &Expr{Type: Float64, Eval: func(*Thread) interface{} { return f }}, nil
+}
+
+func compileString(val string) (*Expr, error) {
+	s := unquote(val)
+	return &Expr{Type: String, Eval: func(*Thread) interface{} { return s }}, nil
+}
+
+// unquote strips the surrounding quotes a SQL STRING literal carries in
+// its printing form; it does not otherwise unescape the body.
+func unquote(s string) string {
+	if len(s) >= 2 {
+		if q := s[0]; (q == '\'' || q == '"') && s[len(s)-1] == q {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	default:
+		return 0
+	}
+}
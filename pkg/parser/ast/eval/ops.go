@@ -0,0 +1,249 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+func (c *Compiler) compileUnary(e *ast.Expr) (*Expr, error) {
+	od, err := c.Compile(e.Operands()[0])
+	if err != nil {
+		return nil, err
+	}
+	switch e.Operator() {
+	case "-":
+		return &Expr{
+			Type: od.Type,
+			Eval: func(t *Thread) interface{} {
+				if od.Type == Int64 {
+					return -od.Eval(t).(int64)
+				}
+				return -toFloat64(od.Eval(t))
+			},
+		}, nil
+	case "NOT":
+		return &Expr{
+			Type: Bool,
+			Eval: func(t *Thread) interface{} {
+				return !od.Eval(t).(bool)
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("eval: unsupported unary operator %q", e.Operator())
+}
+
+// arith is the dispatch table for binary operators whose result is
+// numeric, promoting int64 to float64 when the operands' types differ.
+var arith = map[string]func(l, r float64) float64{
+	"+": func(l, r float64) float64 { return l + r },
+	"-": func(l, r float64) float64 { return l - r },
+	"*": func(l, r float64) float64 { return l * r },
+	"/": func(l, r float64) float64 { return l / r },
+}
+
+var compare = map[string]func(l, r float64) bool{
+	"<":  func(l, r float64) bool { return l < r },
+	"<=": func(l, r float64) bool { return l <= r },
+	">":  func(l, r float64) bool { return l > r },
+	">=": func(l, r float64) bool { return l >= r },
+	"=":  func(l, r float64) bool { return l == r },
+	"!=": func(l, r float64) bool { return l != r },
+}
+
+func (c *Compiler) compileBinary(e *ast.Expr) (*Expr, error) {
+	od := e.Operands()
+	l, err := c.Compile(od[0])
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.Compile(od[1])
+	if err != nil {
+		return nil, err
+	}
+	op := e.Operator()
+
+	switch op {
+	case "AND", "OR":
+		return compileLogical(op, l, r)
+	case "%":
+		return &Expr{
+			Type: Int64,
+			Eval: func(t *Thread) interface{} {
+				return l.Eval(t).(int64) % r.Eval(t).(int64)
+			},
+		}, nil
+	case "LIKE":
+		return compileLike(l, r), nil
+	case "IN":
+		return compileIn(l, r), nil
+	}
+
+	if fn, ok := arith[op]; ok {
+		resultType := Float64
+		if l.Type == Int64 && r.Type == Int64 {
+			resultType = Int64
+		}
+		return &Expr{
+			Type: resultType,
+			Eval: func(t *Thread) interface{} {
+				v := fn(toFloat64(l.Eval(t)), toFloat64(r.Eval(t)))
+				if resultType == Int64 {
+					return int64(v)
+				}
+				return v
+			},
+		}, nil
+	}
+	if fn, ok := compare[op]; ok {
+		return &Expr{
+			Type: Bool,
+			Eval: func(t *Thread) interface{} {
+				if l.Type == String || r.Type == String {
+					return compareString(op, l.Eval(t).(string), r.Eval(t).(string))
+				}
+				return fn(toFloat64(l.Eval(t)), toFloat64(r.Eval(t)))
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("eval: unsupported binary operator %q", op)
+}
+
+func compareString(op, l, r string) bool {
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "=":
+		return l == r
+	case "!=":
+		return l != r
+	}
+	return false
+}
+
+// compileLogicalChain compiles a flattened n-ary AND/OR node (see
+// ast.flattenAssoc) by folding its operands pairwise with the same
+// short-circuit semantics compileLogical uses for two operands.
+func (c *Compiler) compileLogicalChain(e *ast.Expr) (*Expr, error) {
+	operands := e.Operands()
+	acc, err := c.Compile(operands[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, od := range operands[1:] {
+		next, err := c.Compile(od)
+		if err != nil {
+			return nil, err
+		}
+		acc, err = compileLogical(e.Operator(), acc, next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+func compileLogical(op string, l, r *Expr) (*Expr, error) {
+	if op == "AND" {
+		return &Expr{
+			Type: Bool,
+			Eval: func(t *Thread) interface{} {
+				return l.Eval(t).(bool) && r.Eval(t).(bool)
+			},
+		}, nil
+	}
+	return &Expr{
+		Type: Bool,
+		Eval: func(t *Thread) interface{} {
+			return l.Eval(t).(bool) || r.Eval(t).(bool)
+		},
+	}, nil
+}
+
+func compileLike(l, r *Expr) *Expr {
+	return &Expr{
+		Type: Bool,
+		Eval: func(t *Thread) interface{} {
+			return matchLike(l.Eval(t).(string), r.Eval(t).(string))
+		},
+	}
+}
+
+// matchLike implements the SQL LIKE "%"/"_" wildcards without
+// regexp-compiling the pattern on every row.
+func matchLike(s, pattern string) bool {
+	parts := strings.Split(pattern, "%")
+	if len(parts) == 1 {
+		return matchLikeSegment(s, pattern) && len(s) == len(pattern)
+	}
+	if len(s) < len(parts[0]) || !matchLikeSegment(s[:len(parts[0])], parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, p := range parts[1 : len(parts)-1] {
+		i := indexLikeSegment(s, p)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(p):]
+	}
+	last := parts[len(parts)-1]
+	return len(s) >= len(last) && matchLikeSegment(s[len(s)-len(last):], last)
+}
+
+func matchLikeSegment(s, seg string) bool {
+	if len(s) != len(seg) {
+		return false
+	}
+	for i := 0; i < len(seg); i++ {
+		if seg[i] != '_' && seg[i] != s[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func indexLikeSegment(s, seg string) int {
+	for i := 0; i+len(seg) <= len(s); i++ {
+		if matchLikeSegment(s[i:i+len(seg)], seg) {
+			return i
+		}
+	}
+	return -1
+}
+
+func compileIn(l, r *Expr) *Expr {
+	return &Expr{
+		Type: Bool,
+		Eval: func(t *Thread) interface{} {
+			v := l.Eval(t)
+			set, _ := r.Eval(t).([]interface{})
+			for _, x := range set {
+				if x == v {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
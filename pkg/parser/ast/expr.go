@@ -18,6 +18,18 @@ import (
 	"strings"
 )
 
+// Literal kind constants identify what a literal Expr's typ holds:
+// NUMBER and STRING are constants, and IDENT is a column/variable
+// reference.  They are defined here, rather than imported from the
+// goyacc-generated parser package, for the same reason funcall is an
+// ad-hoc field below: ast must not depend on generated parser code, yet
+// callers need a stable way to tell literal kinds apart.
+const (
+	NUMBER = iota + 1
+	STRING
+	IDENT
+)
+
 // Expr defines an expression.
 type Expr struct {
 	// The design inherits from Lisp's S-expression.  It represents a
@@ -72,6 +84,38 @@ func (e Expr) IsBinary() bool {
 		len(e.sexp) == 3
 }
 
+// Typ returns the lexer token type of e.  For a literal, this is the
+// token type produced by the parser (e.g. NUMBER, STRING, IDENT); for a
+// compound expression, it is always 0.
+func (e *Expr) Typ() int {
+	return e.typ
+}
+
+// Val returns the printing form of a literal expression, e.g. the digits
+// of a NUMBER or the identifier name of an IDENT.  It is empty for a
+// compound expression.
+func (e *Expr) Val() string {
+	return e.val
+}
+
+// Operator returns the operator, function name, or bracket of a compound
+// expression, i.e. sexp[0].val.  It is empty for a literal.
+func (e *Expr) Operator() string {
+	if e.IsLiteral() || len(e.sexp) == 0 {
+		return ""
+	}
+	return e.sexp[0].val
+}
+
+// Operands returns the operands of a compound expression, i.e. sexp[1:].
+// It is nil for a literal.
+func (e *Expr) Operands() ExprList {
+	if e.IsLiteral() || len(e.sexp) < 2 {
+		return nil
+	}
+	return e.sexp[1:]
+}
+
 // NewLiteral returns a literal expression.
 func NewLiteral(typ int, val string) (*Expr, error) {
 	if typ == 0 {
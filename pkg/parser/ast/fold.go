@@ -0,0 +1,335 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import "strconv"
+
+// PureFuncs is the registry of builtin functions Fold may evaluate at
+// compile time.  ast has no notion of which SQL builtins are
+// side-effect-free, so a caller that does know should populate this map;
+// Fold leaves any funcall whose name is absent from it untouched.
+var PureFuncs = map[string]func(args ExprList) (*Expr, bool){}
+
+// Fold performs a bottom-up constant-folding and simplification rewrite
+// of e: arithmetic, boolean algebra, and comparisons between literal
+// operands are evaluated at compile time, pure funcalls listed in
+// PureFuncs are evaluated the same way, and chained AND/OR are flattened
+// into a single n-ary node to enable predicate pushdown later.  Fold
+// returns e itself, unchanged, for any subtree it cannot simplify.
+func Fold(e *Expr) *Expr {
+	return Rewrite(e, foldNode)
+}
+
+func foldNode(e *Expr) *Expr {
+	switch {
+	case e.IsBinary():
+		if f := foldBinary(e); f != nil {
+			return f
+		}
+	case e.IsUnary():
+		if f := foldUnary(e); f != nil {
+			return f
+		}
+	case e.IsFuncall():
+		if f := foldFuncall(e); f != nil {
+			return f
+		}
+	}
+	return e
+}
+
+func foldBinary(e *Expr) *Expr {
+	od := e.Operands()
+	l, r, op := od[0], od[1], e.Operator()
+
+	switch op {
+	case "AND":
+		if f := foldAndOperand(l, r); f != nil {
+			return f
+		}
+		return flattenAssoc(e, op)
+	case "OR":
+		return flattenOr(e, l, r)
+	}
+
+	if !isConstLiteral(l) || !isConstLiteral(r) {
+		return nil
+	}
+	switch op {
+	case "+", "-", "*", "/", "%":
+		return foldArith(op, l, r)
+	case "<", "<=", ">", ">=", "=", "!=":
+		return foldCompare(op, l, r)
+	}
+	return nil
+}
+
+// foldAndOperand implements "TRUE AND x -> x" and "FALSE AND x ->
+// FALSE" (in either operand position); it returns nil if neither
+// operand is a boolean literal.
+func foldAndOperand(l, r *Expr) *Expr {
+	if isBoolLiteral(l) {
+		if boolVal(l) {
+			return r
+		}
+		return l
+	}
+	if isBoolLiteral(r) {
+		if boolVal(r) {
+			return l
+		}
+		return r
+	}
+	return nil
+}
+
+// flattenOr implements "TRUE OR x -> TRUE" and "FALSE OR x -> x", then
+// falls back to flattening a chained OR.
+func flattenOr(e, l, r *Expr) *Expr {
+	if isBoolLiteral(l) {
+		if boolVal(l) {
+			return l
+		}
+		return r
+	}
+	if isBoolLiteral(r) {
+		if boolVal(r) {
+			return r
+		}
+		return l
+	}
+	return flattenAssoc(e, "OR")
+}
+
+// isConstLiteral reports whether e is a NUMBER or STRING literal, as
+// opposed to an IDENT (a column/variable reference).  Fold must not
+// treat IDENT operands as constants: "age = age" or "a = b" depend on
+// the row at runtime and folding them to TRUE/FALSE would silently
+// rewrite query semantics.
+func isConstLiteral(e *Expr) bool {
+	return e.IsLiteral() && (e.Typ() == NUMBER || e.Typ() == STRING)
+}
+
+func isBoolLiteral(e *Expr) bool {
+	return e.IsLiteral() && (e.Val() == "TRUE" || e.Val() == "FALSE")
+}
+
+func boolVal(e *Expr) bool {
+	return e.Val() == "TRUE"
+}
+
+func foldUnary(e *Expr) *Expr {
+	x := e.Operands()[0]
+	switch e.Operator() {
+	case "NOT":
+		if x.IsUnary() && x.Operator() == "NOT" {
+			return x.Operands()[0] // NOT NOT x -> x
+		}
+		if isBoolLiteral(x) {
+			return boolLiteral(x, !boolVal(x))
+		}
+	case "-":
+		if n, ok := parseNumber(x); ok {
+			if n.isInt {
+				return newIntLiteral(x, -n.i)
+			}
+			return newFloatLiteral(x, -n.f)
+		}
+	}
+	return nil
+}
+
+func foldFuncall(e *Expr) *Expr {
+	fn, ok := PureFuncs[e.Operator()]
+	if !ok {
+		return nil
+	}
+	for _, a := range e.Operands() {
+		if !isConstLiteral(a) {
+			return nil
+		}
+	}
+	if folded, ok := fn(e.Operands()); ok {
+		return folded
+	}
+	return nil
+}
+
+// numLiteral decomposes a NUMBER literal into its integer and floating
+// point interpretation.
+type numLiteral struct {
+	i     int64
+	f     float64
+	isInt bool
+}
+
+func parseNumber(e *Expr) (numLiteral, bool) {
+	if !e.IsLiteral() {
+		return numLiteral{}, false
+	}
+	if i, err := strconv.ParseInt(e.Val(), 10, 64); err == nil {
+		return numLiteral{i: i, f: float64(i), isInt: true}, true
+	}
+	if f, err := strconv.ParseFloat(e.Val(), 64); err == nil {
+		return numLiteral{f: f}, true
+	}
+	return numLiteral{}, false
+}
+
+func newIntLiteral(like *Expr, i int64) *Expr {
+	e, _ := NewLiteral(like.typ, strconv.FormatInt(i, 10))
+	return e
+}
+
+func newFloatLiteral(like *Expr, f float64) *Expr {
+	e, _ := NewLiteral(like.typ, strconv.FormatFloat(f, 'g', -1, 64))
+	return e
+}
+
+func boolLiteral(like *Expr, b bool) *Expr {
+	val := "FALSE"
+	if b {
+		val = "TRUE"
+	}
+	e, _ := NewLiteral(like.typ, val)
+	return e
+}
+
+func foldArith(op string, l, r *Expr) *Expr {
+	ln, lok := parseNumber(l)
+	rn, rok := parseNumber(r)
+	if !lok || !rok {
+		return nil
+	}
+	bothInt := ln.isInt && rn.isInt
+
+	switch op {
+	case "+":
+		if bothInt {
+			return newIntLiteral(l, ln.i+rn.i)
+		}
+		return newFloatLiteral(l, ln.f+rn.f)
+	case "-":
+		if bothInt {
+			return newIntLiteral(l, ln.i-rn.i)
+		}
+		return newFloatLiteral(l, ln.f-rn.f)
+	case "*":
+		if bothInt {
+			return newIntLiteral(l, ln.i*rn.i)
+		}
+		return newFloatLiteral(l, ln.f*rn.f)
+	case "/":
+		if bothInt {
+			if rn.i == 0 {
+				return nil
+			}
+			return newIntLiteral(l, ln.i/rn.i)
+		}
+		if rn.f == 0 {
+			return nil
+		}
+		return newFloatLiteral(l, ln.f/rn.f)
+	case "%":
+		if !bothInt || rn.i == 0 {
+			return nil
+		}
+		return newIntLiteral(l, ln.i%rn.i)
+	}
+	return nil
+}
+
+func foldCompare(op string, l, r *Expr) *Expr {
+	var lt bool
+	var eq bool
+	if ln, lok := parseNumber(l); lok {
+		rn, rok := parseNumber(r)
+		if !rok {
+			return nil
+		}
+		if ln.isInt && rn.isInt {
+			// Compare as int64; comparing via float64 would lose
+			// precision for values beyond 2^53.
+			lt, eq = ln.i < rn.i, ln.i == rn.i
+		} else {
+			lt, eq = ln.f < rn.f, ln.f == rn.f
+		}
+	} else if l.Typ() == r.Typ() {
+		lt, eq = l.Val() < r.Val(), l.Val() == r.Val()
+	} else {
+		return nil
+	}
+
+	var b bool
+	switch op {
+	case "<":
+		b = lt
+	case "<=":
+		b = lt || eq
+	case ">":
+		b = !lt && !eq
+	case ">=":
+		b = !lt || eq
+	case "=":
+		b = eq
+	case "!=":
+		b = !eq
+	default:
+		return nil
+	}
+	return boolLiteral(l, b)
+}
+
+// isAssocChain reports whether e is itself a flattened chain of op (as
+// produced by flattenAssoc) or an unflattened binary application of op.
+func isAssocChain(e *Expr, op string) bool {
+	return (e.IsBinary() || e.IsFuncall()) && e.Operator() == op
+}
+
+// flattenAssoc collapses a chain of the same associative operator (e.g.
+// (a AND b) AND c) into a single n-ary node, so that later passes (e.g.
+// predicate pushdown) can iterate the conjuncts/disjuncts directly
+// instead of unwinding nested binary nodes.  Expr's only n-ary shape,
+// IsVariadic, is reserved for "[...]"/"(...)" list construction and
+// requires sexp[0].val to literally be "[" or "(", so it cannot also
+// carry an "AND"/"OR" operator; the flattened node therefore reuses the
+// funcall representation instead, with IsFuncall true and Operator()
+// returning "AND"/"OR".  Callers that want to recognize a flattened
+// chain should match on Operator() being "AND"/"OR" (which is true both
+// for the original binary node and for a flattened one), not on
+// IsVariadic(); ast/eval's Compile does exactly that when compiling a
+// folded tree, handing such a node to compileLogicalChain.  flattenAssoc returns nil if e is a plain two-operand
+// application with nothing to flatten.
+func flattenAssoc(e *Expr, op string) *Expr {
+	var operands ExprList
+	var collect func(x *Expr)
+	collect = func(x *Expr) {
+		if isAssocChain(x, op) {
+			for _, od := range x.Operands() {
+				collect(od)
+			}
+			return
+		}
+		operands = append(operands, x)
+	}
+	collect(e)
+	if len(operands) <= 2 {
+		return nil
+	}
+	flat, err := NewFuncall(e.sexp[0].typ, op, operands)
+	if err != nil {
+		return nil
+	}
+	return flat
+}
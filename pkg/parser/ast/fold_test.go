@@ -0,0 +1,154 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import "testing"
+
+func mustNum(t *testing.T, v string) *Expr {
+	e, err := NewLiteral(NUMBER, v)
+	if err != nil {
+		t.Fatalf("NewLiteral(NUMBER, %q) returned error: %v", v, err)
+	}
+	return e
+}
+
+func mustBool(t *testing.T, b bool) *Expr {
+	v := "FALSE"
+	if b {
+		v = "TRUE"
+	}
+	e, err := NewLiteral(IDENT, v)
+	if err != nil {
+		t.Fatalf("NewLiteral(IDENT, %q) returned error: %v", v, err)
+	}
+	return e
+}
+
+func mustBin(t *testing.T, op string, l, r *Expr) *Expr {
+	e, err := NewBinary(int(op[0]), op, l, r)
+	if err != nil {
+		t.Fatalf("NewBinary(%q) returned error: %v", op, err)
+	}
+	return e
+}
+
+func mustUnary(t *testing.T, op string, od *Expr) *Expr {
+	e, err := NewUnary(int(op[0]), op, od)
+	if err != nil {
+		t.Fatalf("NewUnary(%q) returned error: %v", op, err)
+	}
+	return e
+}
+
+func TestFoldArithIntegrality(t *testing.T) {
+	cases := []struct {
+		expr *Expr
+		want string
+	}{
+		{mustBin(t, "+", mustNum(t, "1"), mustBin(t, "*", mustNum(t, "2"), mustNum(t, "3"))), "7"},
+		{mustBin(t, "+", mustNum(t, "1"), mustNum(t, "2.5")), "3.5"},
+		{mustBin(t, "/", mustNum(t, "7"), mustNum(t, "2")), "3"},
+	}
+	for _, c := range cases {
+		got := Fold(c.expr)
+		if !got.IsLiteral() || got.Val() != c.want {
+			t.Errorf("Fold(%v) = %v, want literal %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestFoldDivisionByZeroLeavesNodeUnchanged(t *testing.T) {
+	e := mustBin(t, "/", mustNum(t, "1"), mustNum(t, "0"))
+	got := Fold(e)
+	if got != e {
+		t.Fatalf("Fold(1/0) should return the original node unchanged, got %v", got)
+	}
+}
+
+func TestFoldBooleanAlgebra(t *testing.T) {
+	x := mustIdentE(t, "x")
+
+	andTrue := mustBin(t, "AND", mustBool(t, true), x)
+	if got := Fold(andTrue); got != x {
+		t.Errorf("Fold(TRUE AND x) = %v, want x", got)
+	}
+
+	orFalse := mustBin(t, "OR", mustBool(t, false), x)
+	if got := Fold(orFalse); got != x {
+		t.Errorf("Fold(FALSE OR x) = %v, want x", got)
+	}
+
+	notNot := mustUnary(t, "NOT", mustUnary(t, "NOT", x))
+	if got := Fold(notNot); got != x {
+		t.Errorf("Fold(NOT NOT x) = %v, want x", got)
+	}
+}
+
+func TestFoldFlattensChainedAndOr(t *testing.T) {
+	a, b, c := mustIdentE(t, "a"), mustIdentE(t, "b"), mustIdentE(t, "c")
+	chain := mustBin(t, "AND", mustBin(t, "AND", a, b), c)
+	got := Fold(chain)
+	if !got.IsFuncall() || got.Operator() != "AND" {
+		t.Fatalf("Fold((a AND b) AND c) = %v, want a flattened 3-way AND", got)
+	}
+	if len(got.Operands()) != 3 {
+		t.Fatalf("Fold((a AND b) AND c) has %d operands, want 3", len(got.Operands()))
+	}
+}
+
+func TestFoldDoesNotCollapseComparisonsBetweenIdentifiers(t *testing.T) {
+	// Regression test: foldCompare used to treat any two same-typ
+	// literals as constants, so "age = age" and "a = b" (both IDENTs)
+	// were folded to TRUE/FALSE at compile time, which is wrong because
+	// their values depend on the row at runtime.
+	age1, age2 := mustIdentE(t, "age"), mustIdentE(t, "age")
+	same := mustBin(t, "=", age1, age2)
+	if got := Fold(same); got != same {
+		t.Errorf("Fold(age = age) = %v, want the comparison left unfolded", got)
+	}
+
+	a, b := mustIdentE(t, "a"), mustIdentE(t, "b")
+	diff := mustBin(t, "=", a, b)
+	if got := Fold(diff); got != diff {
+		t.Errorf("Fold(a = b) = %v, want the comparison left unfolded", got)
+	}
+}
+
+func TestFoldCompareIntegersWithoutFloatPrecisionLoss(t *testing.T) {
+	// Regression test: foldCompare used to compare NUMBER operands via
+	// float64 even when both were integers, so two distinct int64
+	// values beyond 2^53 (which float64 can no longer tell apart) were
+	// folded to TRUE for "=".
+	e := mustBin(t, "=", mustNum(t, "9007199254740993"), mustNum(t, "9007199254740992"))
+	got := Fold(e)
+	if !got.IsLiteral() || got.Val() != "FALSE" {
+		t.Errorf("Fold(9007199254740993 = 9007199254740992) = %v, want FALSE", got)
+	}
+}
+
+func TestFoldStillCollapsesComparisonsBetweenLiterals(t *testing.T) {
+	e := mustBin(t, "=", mustNum(t, "3"), mustNum(t, "3"))
+	got := Fold(e)
+	if !got.IsLiteral() || got.Val() != "TRUE" {
+		t.Errorf("Fold(3 = 3) = %v, want TRUE", got)
+	}
+}
+
+func mustIdentE(t *testing.T, name string) *Expr {
+	e, err := NewLiteral(IDENT, name)
+	if err != nil {
+		t.Fatalf("NewLiteral(IDENT, %q) returned error: %v", name, err)
+	}
+	return e
+}
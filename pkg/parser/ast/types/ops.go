@@ -0,0 +1,175 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+func numeric(t Type) bool {
+	return t == Untyped || t == Int64 || t == Float64
+}
+
+// promote resolves the result type of a binary numeric operator given
+// its operand types, following int->float and untyped->context-type
+// promotion: Float64 wins over Int64, which wins over Untyped.
+func promote(lt, rt Type) Type {
+	switch {
+	case lt == Float64 || rt == Float64:
+		return Float64
+	case lt == Int64 || rt == Int64:
+		return Int64
+	default:
+		return Untyped
+	}
+}
+
+func (c *Checker) checkUnary(e *ast.Expr) Type {
+	t := c.check(e.Operands()[0])
+	if t == Invalid {
+		return Invalid
+	}
+	switch e.Operator() {
+	case "-":
+		if !numeric(t) {
+			return c.errorf(e, "operator - requires a numeric operand, got %v", t)
+		}
+		return t
+	case "NOT":
+		if t != Bool {
+			return c.errorf(e, "operator NOT requires a bool operand, got %v", t)
+		}
+		return Bool
+	default:
+		return c.errorf(e, "unsupported unary operator %q", e.Operator())
+	}
+}
+
+func (c *Checker) checkBinary(e *ast.Expr) Type {
+	od := e.Operands()
+	lt := c.check(od[0])
+	rt := c.check(od[1])
+	op := e.Operator()
+
+	switch op {
+	case "AND", "OR":
+		return c.checkLogical(e, op, lt, rt)
+	case "+", "-", "*", "/", "%":
+		return c.checkArith(e, op, lt, rt)
+	case "<", "<=", ">", ">=", "=", "!=":
+		return c.checkCompare(e, op, lt, rt)
+	case "LIKE":
+		return c.checkLike(e, lt, rt)
+	case "IN":
+		return Bool
+	default:
+		return c.errorf(e, "unknown binary operator %q", op)
+	}
+}
+
+func (c *Checker) checkLogical(e *ast.Expr, op string, lt, rt Type) Type {
+	if lt == Invalid || rt == Invalid {
+		return Invalid
+	}
+	if lt != Bool || rt != Bool {
+		return c.errorf(e, "operator %s requires bool operands, got %v and %v", op, lt, rt)
+	}
+	return Bool
+}
+
+func (c *Checker) checkArith(e *ast.Expr, op string, lt, rt Type) Type {
+	if lt == Invalid || rt == Invalid {
+		return Invalid
+	}
+	if !numeric(lt) || !numeric(rt) {
+		return c.errorf(e, "operator %s requires numeric operands, got %v and %v", op, lt, rt)
+	}
+	if op == "%" && (lt == Float64 || rt == Float64) {
+		return c.errorf(e, "operator %% requires integer operands, got %v and %v", lt, rt)
+	}
+	return promote(lt, rt)
+}
+
+func (c *Checker) checkCompare(e *ast.Expr, op string, lt, rt Type) Type {
+	if lt == Invalid || rt == Invalid {
+		return Bool
+	}
+	switch {
+	case numeric(lt) && numeric(rt):
+		return Bool
+	case lt == String && rt == String, lt == Timestamp && rt == Timestamp, lt == Bool && rt == Bool:
+		return Bool
+	default:
+		c.errorf(e, "operator %s cannot compare %v and %v", op, lt, rt)
+		return Bool
+	}
+}
+
+func (c *Checker) checkLike(e *ast.Expr, lt, rt Type) Type {
+	if lt == Invalid || rt == Invalid {
+		return Bool
+	}
+	if lt != String || rt != String {
+		c.errorf(e, "operator LIKE requires string operands, got %v and %v", lt, rt)
+	}
+	return Bool
+}
+
+func (c *Checker) checkFuncall(e *ast.Expr) Type {
+	sig, ok := c.Funcs[e.Operator()]
+	if !ok {
+		return c.errorf(e, "unknown function %q", e.Operator())
+	}
+	args := e.Operands()
+	if !sig.Variadic && len(args) != len(sig.Args) {
+		return c.errorf(e, "function %s takes %d argument(s), got %d", e.Operator(), len(sig.Args), len(args))
+	}
+	if sig.Variadic && len(args) < len(sig.Args)-1 {
+		return c.errorf(e, "function %s takes at least %d argument(s), got %d", e.Operator(), len(sig.Args)-1, len(args))
+	}
+	valid := true
+	for i, a := range args {
+		wantIdx := i
+		if sig.Variadic && i >= len(sig.Args)-1 {
+			wantIdx = len(sig.Args) - 1
+		}
+		want := sig.Args[wantIdx]
+		got := c.check(a)
+		if got == Invalid {
+			valid = false
+			continue
+		}
+		if !assignable(got, want) {
+			c.errorf(a, "argument %d of %s: cannot use %v as %v", i+1, e.Operator(), got, want)
+			valid = false
+		}
+	}
+	if !valid {
+		return Invalid
+	}
+	return sig.Result
+}
+
+// assignable reports whether a value of type got may be used where want
+// is expected, allowing an untyped constant to take on any numeric
+// context type.
+func assignable(got, want Type) bool {
+	if got == want {
+		return true
+	}
+	if got == Untyped && numeric(want) {
+		return true
+	}
+	return false
+}
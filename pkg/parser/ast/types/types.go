@@ -0,0 +1,203 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types implements a semantic type-checker pass over ast.Expr,
+// following the operand-mode design of go/types/expr.go: each node kind
+// is checked by a function that determines the node's mode (constant,
+// variable, value, or invalid) and its Type, and binary operators are
+// checked for compatible operand types under a fixed promotion order.
+package types
+
+import (
+	"fmt"
+
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+// Type is the semantic type of an expression or a schema column.
+type Type int
+
+// The types a Checker can infer.
+const (
+	Invalid Type = iota
+	// Untyped is the type of a NUMBER literal that has not yet been
+	// used in a context that fixes it to Int64 or Float64, mirroring
+	// go/types' untyped constants: both "age > 3.14" and "age > 3"
+	// type-check regardless of whether age is Int64 or Float64.
+	Untyped
+	Int64
+	Float64
+	String
+	Bool
+	Timestamp
+)
+
+func (t Type) String() string {
+	switch t {
+	case Untyped:
+		return "untyped number"
+	case Int64:
+		return "int64"
+	case Float64:
+		return "float64"
+	case String:
+		return "string"
+	case Bool:
+		return "bool"
+	case Timestamp:
+		return "timestamp"
+	default:
+		return "invalid"
+	}
+}
+
+// mode classifies how an operand's Type was derived.
+type mode int
+
+const (
+	invalid mode = iota
+	constant
+	variable
+	value
+)
+
+// Signature is a builtin function's type signature.  If Variadic is
+// true, the last entry of Args is repeated for any trailing arguments.
+type Signature struct {
+	Args     []Type
+	Result   Type
+	Variadic bool
+}
+
+// FuncRegistry describes the signatures of builtin functions visible to
+// a Checker.
+type FuncRegistry map[string]Signature
+
+// TypeInfo maps every subexpression a Checker has visited to its
+// inferred Type.
+type TypeInfo struct {
+	Types map[*ast.Expr]Type
+}
+
+// Diagnostic is one type error or warning produced while checking an
+// expression.
+type Diagnostic struct {
+	Expr    *ast.Expr
+	Message string
+}
+
+func (d *Diagnostic) Error() string {
+	return d.Message
+}
+
+// Checker type-checks ast.Expr trees against a column schema and a set
+// of builtin function signatures.
+type Checker struct {
+	Schema map[string]Type
+	Funcs  FuncRegistry
+
+	info  TypeInfo
+	diags []*Diagnostic
+}
+
+// NewChecker returns a Checker that resolves identifiers against schema
+// and funcalls against funcs.
+func NewChecker(schema map[string]Type, funcs FuncRegistry) *Checker {
+	return &Checker{
+		Schema: schema,
+		Funcs:  funcs,
+		info:   TypeInfo{Types: make(map[*ast.Expr]Type)},
+	}
+}
+
+// CheckExpr type-checks e and returns its Type.  If checking e raised
+// any diagnostics, CheckExpr returns the first one as an error; the
+// full list remains available from Diagnostics.
+func (c *Checker) CheckExpr(e *ast.Expr) (Type, error) {
+	before := len(c.diags)
+	t := c.check(e)
+	if len(c.diags) > before {
+		return t, c.diags[before]
+	}
+	return t, nil
+}
+
+// Info returns the TypeInfo accumulated by every CheckExpr call so far.
+func (c *Checker) Info() TypeInfo {
+	return c.info
+}
+
+// Diagnostics returns every diagnostic raised by CheckExpr calls so far.
+func (c *Checker) Diagnostics() []*Diagnostic {
+	return c.diags
+}
+
+// operand is an expression's type together with the mode it was
+// derived under, mirroring go/types' operand.
+type operand struct {
+	mode mode
+	typ  Type
+}
+
+func (c *Checker) check(e *ast.Expr) Type {
+	op := c.check2(e)
+	c.info.Types[e] = op.typ
+	return op.typ
+}
+
+func (c *Checker) check2(e *ast.Expr) operand {
+	switch {
+	case e.IsLiteral():
+		return c.checkLiteral(e)
+	case e.IsFuncall():
+		return operand{value, c.checkFuncall(e)}
+	case e.IsVariadic():
+		return operand{value, c.checkVariadic(e)}
+	case e.IsUnary():
+		return operand{value, c.checkUnary(e)}
+	case e.IsBinary():
+		return operand{value, c.checkBinary(e)}
+	default:
+		return operand{invalid, c.errorf(e, "cannot type-check expression %q", e)}
+	}
+}
+
+func (c *Checker) errorf(e *ast.Expr, format string, args ...interface{}) Type {
+	c.diags = append(c.diags, &Diagnostic{Expr: e, Message: fmt.Sprintf(format, args...)})
+	return Invalid
+}
+
+func (c *Checker) checkLiteral(e *ast.Expr) operand {
+	switch e.Typ() {
+	case ast.NUMBER:
+		return operand{constant, Untyped}
+	case ast.STRING:
+		return operand{constant, String}
+	case ast.IDENT:
+		t, ok := c.Schema[e.Val()]
+		if !ok {
+			return operand{invalid, c.errorf(e, "unknown identifier %q", e.Val())}
+		}
+		return operand{variable, t}
+	default:
+		return operand{invalid, c.errorf(e, "unsupported literal type %d in %q", e.Typ(), e)}
+	}
+}
+
+func (c *Checker) checkVariadic(e *ast.Expr) Type {
+	t := Invalid
+	for _, od := range e.Operands() {
+		t = c.check(od)
+	}
+	return t
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"sqlflow.org/sqlflow/pkg/parser/ast"
+)
+
+func num(t *testing.T, v string) *ast.Expr {
+	e, err := ast.NewLiteral(ast.NUMBER, v)
+	if err != nil {
+		t.Fatalf("NewLiteral(NUMBER, %q) returned error: %v", v, err)
+	}
+	return e
+}
+
+func ident(t *testing.T, name string) *ast.Expr {
+	e, err := ast.NewLiteral(ast.IDENT, name)
+	if err != nil {
+		t.Fatalf("NewLiteral(IDENT, %q) returned error: %v", name, err)
+	}
+	return e
+}
+
+func binary(t *testing.T, op string, l, r *ast.Expr) *ast.Expr {
+	e, err := ast.NewBinary(int(op[0]), op, l, r)
+	if err != nil {
+		t.Fatalf("NewBinary(%q) returned error: %v", op, err)
+	}
+	return e
+}
+
+func TestCheckExprUntypedNumberAgainstIntOrFloatColumn(t *testing.T) {
+	schema := map[string]Type{"age": Int64}
+	cases := []*ast.Expr{
+		binary(t, ">", ident(t, "age"), num(t, "3")),
+		binary(t, ">", ident(t, "age"), num(t, "3.14")),
+	}
+	for _, e := range cases {
+		c := NewChecker(schema, nil)
+		typ, err := c.CheckExpr(e)
+		if err != nil {
+			t.Errorf("CheckExpr(%v) returned error: %v", e, err)
+		}
+		if typ != Bool {
+			t.Errorf("CheckExpr(%v) = %v, want Bool", e, typ)
+		}
+	}
+}
+
+func TestCheckExprUnknownIdentifier(t *testing.T) {
+	c := NewChecker(map[string]Type{}, nil)
+	if _, err := c.CheckExpr(ident(t, "nope")); err == nil {
+		t.Fatal("CheckExpr(nope) should have returned a diagnostic for an unknown identifier")
+	}
+}
+
+func TestCheckExprPromotion(t *testing.T) {
+	cases := []struct {
+		lt, rt Type
+		want   Type
+	}{
+		{Int64, Int64, Int64},
+		{Int64, Float64, Float64},
+		{Untyped, Int64, Int64},
+		{Untyped, Untyped, Untyped},
+	}
+	for _, c := range cases {
+		if got := promote(c.lt, c.rt); got != c.want {
+			t.Errorf("promote(%v, %v) = %v, want %v", c.lt, c.rt, got, c.want)
+		}
+	}
+}
+
+func TestCheckFuncallVariadicClampsExtraArgsInsteadOfPanicking(t *testing.T) {
+	funcs := FuncRegistry{
+		"CONCAT": Signature{Args: []Type{String}, Result: String, Variadic: true},
+	}
+	c := NewChecker(nil, funcs)
+	fn, err := ast.NewFuncall(ast.IDENT, "CONCAT", ast.ExprList{
+		mustString(t, "'a'"), mustString(t, "'b'"), mustString(t, "'c'"),
+	})
+	if err != nil {
+		t.Fatalf("NewFuncall returned error: %v", err)
+	}
+	// Regression test: checkFuncall used to index sig.Args[i] before
+	// clamping i to the variadic slot, so a call with more arguments
+	// than sig.Args panicked with an index-out-of-range instead of
+	// producing a diagnostic.
+	typ, err := c.CheckExpr(fn)
+	if err != nil {
+		t.Fatalf("CheckExpr(CONCAT('a','b','c')) returned error: %v", err)
+	}
+	if typ != String {
+		t.Errorf("CheckExpr(CONCAT('a','b','c')) = %v, want String", typ)
+	}
+}
+
+func TestCheckFuncallArityMismatch(t *testing.T) {
+	funcs := FuncRegistry{"ABS": {Args: []Type{Int64}, Result: Int64}}
+	c := NewChecker(nil, funcs)
+	fn, err := ast.NewFuncall(ast.IDENT, "ABS", ast.ExprList{num(t, "1"), num(t, "2")})
+	if err != nil {
+		t.Fatalf("NewFuncall returned error: %v", err)
+	}
+	if _, err := c.CheckExpr(fn); err == nil {
+		t.Fatal("CheckExpr(ABS(1, 2)) should have returned an arity diagnostic")
+	}
+}
+
+func mustString(t *testing.T, v string) *ast.Expr {
+	e, err := ast.NewLiteral(ast.STRING, v)
+	if err != nil {
+		t.Fatalf("NewLiteral(STRING, %q) returned error: %v", v, err)
+	}
+	return e
+}
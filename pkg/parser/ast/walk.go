@@ -0,0 +1,86 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+// Visitor visits expressions.  If Visit returns a non-nil Visitor w,
+// Walk visits each operand of e with w; if Visit returns nil, Walk does
+// not descend into e's operands.
+type Visitor interface {
+	Visit(e *Expr) Visitor
+}
+
+// Walk traverses e in depth-first order, calling v.Visit for e and then,
+// recursively, for each of e's operands.  It is modeled on go/ast.Walk.
+// For a compound expression, sexp[0] (the operator, function name, or
+// bracket) is visited before the operands, so for a funcall the callee
+// literal is visited first.
+func Walk(e *Expr, v Visitor) {
+	if e == nil || v == nil {
+		return
+	}
+	if v = v.Visit(e); v == nil {
+		return
+	}
+	if e.IsLiteral() {
+		return
+	}
+	for _, od := range e.sexp {
+		Walk(od, v)
+	}
+}
+
+type inspector func(*Expr) bool
+
+func (f inspector) Visit(e *Expr) Visitor {
+	if f(e) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses e in depth-first order, calling f for e and then,
+// recursively, for each of e's operands, as long as f keeps returning
+// true.  It is modeled on go/ast.Inspect.
+func Inspect(e *Expr, f func(*Expr) bool) {
+	Walk(e, inspector(f))
+}
+
+// Rewrite returns a new expression tree with f applied to every node,
+// bottom-up: operands are rewritten before the node itself, so f may
+// assume its argument already reflects any substitutions made below it.
+// f may return a different *Expr to substitute in place of the node it
+// was given. Rewrite returns e unchanged (the same pointer) if f
+// substitutes nothing anywhere in the tree.
+func Rewrite(e *Expr, f func(*Expr) *Expr) *Expr {
+	if e == nil {
+		return nil
+	}
+	if e.IsLiteral() {
+		return f(e)
+	}
+	changed := false
+	sexp := make(ExprList, len(e.sexp))
+	for i, od := range e.sexp {
+		r := Rewrite(od, f)
+		if r != od {
+			changed = true
+		}
+		sexp[i] = r
+	}
+	newE := e
+	if changed {
+		newE = &Expr{typ: e.typ, val: e.val, sexp: sexp, funcall: e.funcall}
+	}
+	return f(newE)
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import "testing"
+
+func mustBinary(t *testing.T, op string, l, r *Expr) *Expr {
+	e, err := NewBinary(int(op[0]), op, l, r)
+	if err != nil {
+		t.Fatalf("NewBinary(%q) returned error: %v", op, err)
+	}
+	return e
+}
+
+func mustIdent(t *testing.T, name string) *Expr {
+	e, err := NewLiteral(IDENT, name)
+	if err != nil {
+		t.Fatalf("NewLiteral(IDENT, %q) returned error: %v", name, err)
+	}
+	return e
+}
+
+// collectIdents walks e with Inspect and returns every IDENT's name in
+// the order Inspect visits them.
+func collectIdents(e *Expr) []string {
+	var names []string
+	Inspect(e, func(n *Expr) bool {
+		if n.IsLiteral() && n.Typ() == IDENT {
+			names = append(names, n.Val())
+		}
+		return true
+	})
+	return names
+}
+
+func TestInspectVisitsOperandsInOrder(t *testing.T) {
+	e := mustBinary(t, "+", mustIdent(t, "a"), mustIdent(t, "b"))
+	got := collectIdents(e)
+	want := []string{"a", "b"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("collectIdents(a + b) = %v, want %v", got, want)
+	}
+}
+
+func TestWalkSkipsSubtreeWhenVisitReturnsNil(t *testing.T) {
+	e := mustBinary(t, "+", mustIdent(t, "a"), mustIdent(t, "b"))
+	visits := 0
+	Walk(e, visitFunc(func(n *Expr) Visitor {
+		visits++
+		return nil // never descend, so only the root itself is visited
+	}))
+	if visits != 1 {
+		t.Fatalf("Walk visited %d nodes, want 1 (Visit returning nil must skip the subtree)", visits)
+	}
+}
+
+// visitFunc adapts a plain func to the Visitor interface for tests that
+// don't need a stateful visitor type.
+type visitFunc func(*Expr) Visitor
+
+func (f visitFunc) Visit(e *Expr) Visitor { return f(e) }
+
+func TestRewriteReturnsSamePointerWhenNothingChanges(t *testing.T) {
+	e := mustBinary(t, "+", mustIdent(t, "a"), mustIdent(t, "b"))
+	got := Rewrite(e, func(n *Expr) *Expr { return n })
+	if got != e {
+		t.Fatal("Rewrite should return the original pointer when f substitutes nothing")
+	}
+}
+
+func TestRewriteSubstitutesIdentifier(t *testing.T) {
+	e := mustBinary(t, "+", mustIdent(t, "a"), mustIdent(t, "b"))
+	renamed := Rewrite(e, func(n *Expr) *Expr {
+		if n.IsLiteral() && n.Val() == "a" {
+			r, err := NewLiteral(IDENT, "x")
+			if err != nil {
+				t.Fatalf("NewLiteral returned error: %v", err)
+			}
+			return r
+		}
+		return n
+	})
+	if renamed == e {
+		t.Fatal("Rewrite should return a new tree when a substitution is applied")
+	}
+	if got := collectIdents(renamed); len(got) != 2 || got[0] != "x" || got[1] != "b" {
+		t.Fatalf("collectIdents(renamed) = %v, want [x b]", got)
+	}
+	if got := collectIdents(e); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("original tree was mutated: collectIdents(e) = %v, want [a b]", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// driverNames maps the DSN scheme accepted by -datasource to the
+// database/sql driver name it should be opened with.  The actual driver
+// packages (mysql, hive, maxcompute) register themselves with
+// database/sql via a blank import in the binary that links this package.
+var driverNames = map[string]string{
+	"mysql":      "mysql",
+	"hive":       "hive",
+	"maxcompute": "maxcompute",
+}
+
+// sqlBackend is the default Backend: it opens dsn with database/sql and
+// runs every statement through Query.
+type sqlBackend struct {
+	db *sql.DB
+}
+
+// NewSQLBackend returns a Backend that executes statements against dsn,
+// a DSN of the form "<scheme>://...", where scheme selects the
+// database/sql driver via driverNames.
+func NewSQLBackend(dsn string) (Backend, error) {
+	parts := strings.SplitN(dsn, "://", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("runner: datasource %q is not a <scheme>://... DSN", dsn)
+	}
+	driver, ok := driverNames[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("runner: unsupported datasource scheme %q", parts[0])
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("runner: cannot open datasource %q: %v", dsn, err)
+	}
+	return &sqlBackend{db: db}, nil
+}
+
+func (b *sqlBackend) Execute(ctx context.Context, stmt string) (*Rows, error) {
+	rows, err := b.db.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &Rows{Columns: cols}
+	for rows.Next() {
+		vals := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		result.Data = append(result.Data, vals)
+	}
+	return result, rows.Err()
+}
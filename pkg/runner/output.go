@@ -0,0 +1,84 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+func render(rows *Rows, format string, w io.Writer) error {
+	if rows == nil {
+		return nil
+	}
+	switch format {
+	case "", OutputTable:
+		return renderTable(rows, w)
+	case OutputCSV:
+		return renderCSV(rows, w)
+	case OutputJSON:
+		return renderJSON(rows, w)
+	default:
+		return fmt.Errorf("runner: unknown output format %q", format)
+	}
+}
+
+func renderTable(rows *Rows, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(rows.Columns, "\t"))
+	for _, row := range rows.Data {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = fmt.Sprintf("%v", v)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func renderCSV(rows *Rows, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(rows.Columns); err != nil {
+		return err
+	}
+	for _, row := range rows.Data {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = fmt.Sprintf("%v", v)
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func renderJSON(rows *Rows, w io.Writer) error {
+	records := make([]map[string]interface{}, len(rows.Data))
+	for i, row := range rows.Data {
+		rec := make(map[string]interface{}, len(rows.Columns))
+		for j, col := range rows.Columns {
+			rec[col] = row[j]
+		}
+		records[i] = rec
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
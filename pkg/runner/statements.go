@@ -0,0 +1,53 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// splitStatements splits a SQLFlow program into its individual ";"
+// terminated statements, dropping any that are empty once trimmed.
+func splitStatements(program string) []string {
+	var stmts []string
+	for _, s := range strings.Split(program, ";") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			stmts = append(stmts, s)
+		}
+	}
+	return stmts
+}
+
+// isExtendedStatement reports whether stmt is an extended SQLFlow
+// statement (TO TRAIN/TO PREDICT/TO EXPLAIN/TO EVALUATE) rather than
+// standard SQL.
+func isExtendedStatement(stmt string) bool {
+	upper := strings.ToUpper(stmt)
+	for _, kw := range []string{"TO TRAIN", "TO PREDICT", "TO EXPLAIN", "TO EVALUATE"} {
+		if strings.Contains(upper, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"fmt"
+	"io"
+)
+
+// emitWorkflow writes a workflow YAML for engine ("argo" or "tekton")
+// that runs each of stmts as one step, instead of executing them
+// locally.  The emitted YAML is a minimal, valid skeleton; translating a
+// statement into the step that actually runs it is left to the workflow
+// coupler that owns the SQLFlow-to-$engine template.
+func emitWorkflow(engine string, stmts []string, w io.Writer) error {
+	switch engine {
+	case "argo":
+		return emitArgoWorkflow(stmts, w)
+	case "tekton":
+		return emitTektonWorkflow(stmts, w)
+	default:
+		return fmt.Errorf("runner: unknown workflow engine %q, want \"argo\" or \"tekton\"", engine)
+	}
+}
+
+func emitArgoWorkflow(stmts []string, w io.Writer) error {
+	fmt.Fprintln(w, "apiVersion: argoproj.io/v1alpha1")
+	fmt.Fprintln(w, "kind: Workflow")
+	fmt.Fprintln(w, "metadata:")
+	fmt.Fprintln(w, "  generateName: sqlflow-")
+	fmt.Fprintln(w, "spec:")
+	fmt.Fprintln(w, "  entrypoint: sqlflow")
+	fmt.Fprintln(w, "  templates:")
+	fmt.Fprintln(w, "  - name: sqlflow")
+	fmt.Fprintln(w, "    steps:")
+	for i, stmt := range stmts {
+		fmt.Fprintf(w, "    - - name: step-%d\n", i)
+		fmt.Fprintln(w, "        template: run-statement")
+		fmt.Fprintf(w, "        arguments:\n          parameters:\n          - name: statement\n            value: %q\n", stmt)
+	}
+	return nil
+}
+
+func emitTektonWorkflow(stmts []string, w io.Writer) error {
+	fmt.Fprintln(w, "apiVersion: tekton.dev/v1beta1")
+	fmt.Fprintln(w, "kind: PipelineRun")
+	fmt.Fprintln(w, "metadata:")
+	fmt.Fprintln(w, "  generateName: sqlflow-")
+	fmt.Fprintln(w, "spec:")
+	fmt.Fprintln(w, "  pipelineSpec:")
+	fmt.Fprintln(w, "    tasks:")
+	for i, stmt := range stmts {
+		fmt.Fprintf(w, "    - name: step-%d\n", i)
+		fmt.Fprintln(w, "      taskRef:")
+		fmt.Fprintln(w, "        name: run-statement")
+		fmt.Fprintf(w, "      params:\n      - name: statement\n        value: %q\n", stmt)
+	}
+	return nil
+}